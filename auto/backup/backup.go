@@ -0,0 +1,291 @@
+// Package backup periodically snapshots a node's local directories (the
+// broker's raft log, a data node's shards) and uploads compressed tarballs
+// of each to an object store, so a wiped or replaced node can be brought
+// back up warm via auto/restore.
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/influxdb/influxdb/auto/store"
+)
+
+// Source is a local directory the uploader snapshots, namespaced by Name
+// in the uploaded keys (e.g. "broker", "data").
+type Source struct {
+	Name string
+	Dir  string
+}
+
+// LeaderFunc reports whether this node currently holds Raft leadership.
+// The uploader only runs while it returns true, so a cluster doesn't
+// upload the same generation from multiple nodes during a leadership
+// flip.
+type LeaderFunc func() bool
+
+// Uploader periodically snapshots Sources and uploads them to Store.
+type Uploader struct {
+	Store    store.Store
+	Sources  []Source
+	Interval time.Duration
+	Retain   int // number of generations to keep; 0 means unlimited
+	IsLeader LeaderFunc
+
+	// Uploads and Failures are exposed for the stats system to report.
+	Uploads  uint64
+	Failures uint64
+
+	owner string // random token identifying this Uploader instance in its lease
+
+	stop chan struct{}
+}
+
+// NewUploader returns an Uploader that snapshots sources to st every
+// interval while isLeader returns true, keeping at most retain
+// generations (0 for unlimited).
+func NewUploader(st store.Store, sources []Source, interval time.Duration, retain int, isLeader LeaderFunc) *Uploader {
+	return &Uploader{Store: st, Sources: sources, Interval: interval, Retain: retain, IsLeader: isLeader, owner: newOwnerToken()}
+}
+
+// newOwnerToken returns a random identifier an Uploader uses to recognize
+// a lease as its own (e.g. left over from a tick it crashed mid-upload),
+// so it can reclaim it without waiting out the full TTL.
+func newOwnerToken() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		// Vanishingly unlikely, and a non-unique token only costs us the
+		// self-recognition optimization below, not correctness.
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// Open starts the periodic upload loop in the background.
+func (u *Uploader) Open() {
+	u.stop = make(chan struct{})
+	go u.run(u.stop)
+}
+
+// Close stops the upload loop.
+func (u *Uploader) Close() error {
+	if u.stop != nil {
+		close(u.stop)
+		u.stop = nil
+	}
+	return nil
+}
+
+func (u *Uploader) run(stop chan struct{}) {
+	ticker := time.NewTicker(u.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if !u.IsLeader() {
+				continue
+			}
+			if err := u.uploadGeneration(); err != nil {
+				atomic.AddUint64(&u.Failures, 1)
+				log.Printf("backup: upload failed: %s", err)
+			} else {
+				atomic.AddUint64(&u.Uploads, 1)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// leaseKey names the object that coordinates this uploader's generation
+// uploads across the cluster: a node must hold it to upload a
+// generation, preventing two nodes that both briefly believe they're
+// leader (during a flip) from uploading concurrently. It's namespaced by
+// source name so, e.g., the broker and data uploaders running on the
+// same leader hold independent leases instead of contending for one.
+func (u *Uploader) leaseKey() string {
+	names := make([]string, len(u.Sources))
+	for i, src := range u.Sources {
+		names[i] = src.Name
+	}
+	return "lease." + strings.Join(names, "+")
+}
+
+// leaseTTL bounds how long an unreleased lease is honored before it's
+// considered stale and can be reclaimed, so a leader that crashes
+// mid-upload doesn't lock backups out forever. Uploads finish well
+// within one Interval in the common case, so a few intervals of slack is
+// generous without leaving a long window where two nodes could hold the
+// lease at once after a crash.
+func (u *Uploader) leaseTTL() time.Duration {
+	if u.Interval <= 0 {
+		return time.Minute
+	}
+	return 3 * u.Interval
+}
+
+// lease is the JSON payload stored at a leaseKey.
+type lease struct {
+	Owner   string    `json:"owner"`
+	Expires time.Time `json:"expires"`
+}
+
+func (u *Uploader) uploadGeneration() error {
+	if !u.acquireLease() {
+		return nil
+	}
+	defer u.Store.Delete(u.leaseKey())
+
+	gen := strconv.FormatInt(currentGeneration(), 10)
+	for _, src := range u.Sources {
+		key := fmt.Sprintf("%s/%s.tar.gz", src.Name, gen)
+		if err := u.uploadSource(src, key); err != nil {
+			return fmt.Errorf("backup: %s: %s", src.Name, err)
+		}
+	}
+
+	return u.applyRetention()
+}
+
+// currentGeneration returns a monotonically increasing identifier used to
+// name a snapshot generation.
+var currentGeneration = func() int64 { return time.Now().UnixNano() }
+
+// acquireLease claims this uploader's lease if it's currently free,
+// expired, or already held by this instance (e.g. left over from a tick
+// it crashed mid-upload before releasing). It's a best-effort guard, not
+// a strict lock: a Put that races with another node's Put can still both
+// succeed, but in practice the window around a leadership flip is short
+// enough that this avoids the common case, and an expired lease from a
+// crashed leader is reclaimed automatically instead of disabling backups
+// until someone notices.
+func (u *Uploader) acquireLease() bool {
+	key := u.leaseKey()
+
+	if r, err := u.Store.Get(key); err == nil {
+		var l lease
+		decErr := json.NewDecoder(r).Decode(&l)
+		r.Close()
+		if decErr == nil && l.Owner != u.owner && time.Now().Before(l.Expires) {
+			return false
+		}
+	}
+
+	b, err := json.Marshal(&lease{Owner: u.owner, Expires: time.Now().Add(u.leaseTTL())})
+	if err != nil {
+		log.Printf("backup: failed to encode upload lease: %s", err)
+		return false
+	}
+	if err := u.Store.Put(key, bytes.NewReader(b)); err != nil {
+		log.Printf("backup: failed to acquire upload lease: %s", err)
+		return false
+	}
+	return true
+}
+
+func (u *Uploader) uploadSource(src Source, key string) error {
+	pr, pw := io.Pipe()
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- tarGzipDir(src.Dir, pw)
+		pw.Close()
+	}()
+
+	if err := u.Store.Put(key, pr); err != nil {
+		return err
+	}
+	return <-errCh
+}
+
+// tarGzipDir writes a gzip-compressed tar of dir to w.
+func tarGzipDir(dir string, w io.Writer) error {
+	gw := gzip.NewWriter(w)
+	defer gw.Close()
+
+	tw := tar.NewWriter(gw)
+	defer tw.Close()
+
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = rel
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		// Copy exactly the number of bytes captured in hdr, even though
+		// the broker may still be appending to this file underneath us:
+		// the tar format carries no inner length delimiter once a header
+		// is written, so writing more than hdr.Size would silently
+		// misalign every entry after it.
+		_, err = io.CopyN(tw, f, hdr.Size)
+		if err == io.EOF {
+			return fmt.Errorf("backup: %s: truncated while being archived", path)
+		}
+		return err
+	})
+}
+
+// applyRetention deletes all but the Retain most recent generations of
+// each source.
+func (u *Uploader) applyRetention() error {
+	if u.Retain <= 0 {
+		return nil
+	}
+
+	for _, src := range u.Sources {
+		keys, err := u.Store.List(src.Name + "/")
+		if err != nil {
+			return err
+		}
+		sort.Strings(keys)
+
+		if len(keys) <= u.Retain {
+			continue
+		}
+		for _, key := range keys[:len(keys)-u.Retain] {
+			if err := u.Store.Delete(key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+// Package restore downloads the latest snapshot uploaded by auto/backup
+// and applies it to a local directory, so a node with an empty data
+// directory (freshly provisioned, or replacing a wiped one) can come up
+// warm instead of re-syncing from scratch over Raft.
+package restore
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/influxdb/influxdb/auto/store"
+)
+
+// Restores and Failures are exposed for the stats system to report.
+var (
+	Restores uint64
+	Failures uint64
+)
+
+// Latest downloads and extracts the most recent generation of name (e.g.
+// "broker", "data") from st into dir. It returns false, with a nil error,
+// if no generation has been uploaded yet.
+func Latest(st store.Store, name, dir string) (bool, error) {
+	keys, err := st.List(name + "/")
+	if err != nil {
+		return false, err
+	}
+	if len(keys) == 0 {
+		return false, nil
+	}
+
+	sort.Strings(keys)
+	latest := keys[len(keys)-1]
+
+	r, err := st.Get(latest)
+	if err != nil {
+		return false, err
+	}
+	defer r.Close()
+
+	if err := untarGzip(r, dir); err != nil {
+		return false, fmt.Errorf("restore: %s: %s", latest, err)
+	}
+	return true, nil
+}
+
+// IfEmpty calls Latest only if dir doesn't already contain data, so a node
+// rejoining with existing state never has it clobbered by a restore.
+// except names entries to disregard when deciding whether dir is empty,
+// e.g. a sibling "broker" directory that a combined broker+data node
+// already created inside its data directory before this runs.
+func IfEmpty(st store.Store, name, dir string, except ...string) (bool, error) {
+	empty, err := dirEmpty(dir, except...)
+	if err != nil || !empty {
+		return false, err
+	}
+
+	ok, err := Latest(st, name, dir)
+	if err != nil {
+		Failures++
+		return false, err
+	}
+	if ok {
+		Restores++
+	}
+	return ok, nil
+}
+
+func dirEmpty(dir string, except ...string) (bool, error) {
+	f, err := os.Open(dir)
+	if os.IsNotExist(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	names, err := f.Readdirnames(-1)
+	if err != nil {
+		return false, err
+	}
+	for _, name := range names {
+		if !containsName(except, name) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func untarGzip(r io.Reader, dir string) error {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		path := filepath.Join(dir, hdr.Name)
+		if strings.Contains(hdr.Name, "..") {
+			return fmt.Errorf("restore: invalid path in snapshot: %s", hdr.Name)
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, os.FileMode(hdr.Mode)); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(hdr.Mode))
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(f, tr); err != nil {
+				f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package store
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+)
+
+type azblobStore struct {
+	container azblob.ContainerURL
+	prefix    string
+}
+
+// newAzblobStore builds a Store backed by an Azure Blob Storage container.
+// The account name/key are read from AZURE_STORAGE_ACCOUNT and
+// AZURE_STORAGE_ACCESS_KEY, matching the Azure CLI/SDK convention, since
+// "azblob://container/prefix" URLs have no room for credentials.
+func newAzblobStore(u *url.URL) (Store, error) {
+	accountName, accountKey := azblob.NewSharedKeyCredentialFromEnvironment()
+	credential, err := azblob.NewSharedKeyCredential(accountName, accountKey)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := azblob.NewPipeline(credential, azblob.PipelineOptions{})
+	serviceURL := azblob.NewServiceURL(
+		url.URL{Scheme: "https", Host: accountName + ".blob.core.windows.net"},
+		pipeline,
+	)
+
+	return &azblobStore{
+		container: serviceURL.NewContainerURL(u.Host),
+		prefix:    strings.TrimPrefix(u.Path, "/"),
+	}, nil
+}
+
+func (s *azblobStore) key(key string) string {
+	if s.prefix == "" {
+		return key
+	}
+	return s.prefix + "/" + key
+}
+
+func (s *azblobStore) Put(key string, r io.Reader) error {
+	buf, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	blob := s.container.NewBlockBlobURL(s.key(key))
+	_, err = azblob.UploadBufferToBlockBlob(context.Background(), buf, blob, azblob.UploadToBlockBlobOptions{})
+	return err
+}
+
+func (s *azblobStore) Get(key string) (io.ReadCloser, error) {
+	blob := s.container.NewBlockBlobURL(s.key(key))
+	resp, err := blob.Download(context.Background(), 0, azblob.CountToEnd, azblob.BlobAccessConditions{}, false)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body(azblob.RetryReaderOptions{}), nil
+}
+
+func (s *azblobStore) List(prefix string) ([]string, error) {
+	var keys []string
+	for marker := (azblob.Marker{}); marker.NotDone(); {
+		resp, err := s.container.ListBlobsFlatSegment(context.Background(), marker, azblob.ListBlobsSegmentOptions{
+			Prefix: s.key(prefix),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, blob := range resp.Segment.BlobItems {
+			keys = append(keys, strings.TrimPrefix(blob.Name, s.prefix+"/"))
+		}
+		marker = resp.NextMarker
+	}
+	return keys, nil
+}
+
+func (s *azblobStore) Delete(key string) error {
+	blob := s.container.NewBlockBlobURL(s.key(key))
+	_, err := blob.Delete(context.Background(), azblob.DeleteSnapshotsOptionNone, azblob.BlobAccessConditions{})
+	if serr, ok := err.(azblob.StorageError); ok && serr.ServiceCode() == azblob.ServiceCodeBlobNotFound {
+		return nil
+	}
+	return err
+}
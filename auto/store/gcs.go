@@ -0,0 +1,77 @@
+package store
+
+import (
+	"io"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+)
+
+type gcsStore struct {
+	client *storage.Client
+	bucket string
+	prefix string
+}
+
+func newGCSStore(u *url.URL) (Store, error) {
+	client, err := storage.NewClient(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &gcsStore{
+		client: client,
+		bucket: u.Host,
+		prefix: strings.TrimPrefix(u.Path, "/"),
+	}, nil
+}
+
+func (s *gcsStore) key(key string) string {
+	if s.prefix == "" {
+		return key
+	}
+	return s.prefix + "/" + key
+}
+
+func (s *gcsStore) object(key string) *storage.ObjectHandle {
+	return s.client.Bucket(s.bucket).Object(s.key(key))
+}
+
+func (s *gcsStore) Put(key string, r io.Reader) error {
+	w := s.object(key).NewWriter(context.Background())
+	if _, err := io.Copy(w, r); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func (s *gcsStore) Get(key string) (io.ReadCloser, error) {
+	return s.object(key).NewReader(context.Background())
+}
+
+func (s *gcsStore) List(prefix string) ([]string, error) {
+	var keys []string
+	it := s.client.Bucket(s.bucket).Objects(context.Background(), &storage.Query{Prefix: s.key(prefix)})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, strings.TrimPrefix(attrs.Name, s.prefix+"/"))
+	}
+	return keys, nil
+}
+
+func (s *gcsStore) Delete(key string) error {
+	if err := s.object(key).Delete(context.Background()); err != nil && err != storage.ErrObjectNotExist {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package store
+
+import (
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+type s3Store struct {
+	client *s3.S3
+	bucket string
+	prefix string
+}
+
+func newS3Store(u *url.URL) (Store, error) {
+	sess, err := session.NewSession()
+	if err != nil {
+		return nil, err
+	}
+	return &s3Store{
+		client: s3.New(sess),
+		bucket: u.Host,
+		prefix: strings.TrimPrefix(u.Path, "/"),
+	}, nil
+}
+
+func (s *s3Store) key(key string) string {
+	if s.prefix == "" {
+		return key
+	}
+	return s.prefix + "/" + key
+}
+
+func (s *s3Store) Put(key string, r io.Reader) error {
+	buf, err := readAll(r)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(key)),
+		Body:   bytesReader(buf),
+	})
+	return err
+}
+
+func (s *s3Store) Get(key string) (io.ReadCloser, error) {
+	out, err := s.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(key)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Body, nil
+}
+
+func (s *s3Store) List(prefix string) ([]string, error) {
+	var keys []string
+	err := s.client.ListObjectsPages(&s3.ListObjectsInput{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(s.key(prefix)),
+	}, func(page *s3.ListObjectsOutput, lastPage bool) bool {
+		for _, obj := range page.Contents {
+			keys = append(keys, strings.TrimPrefix(*obj.Key, s.prefix+"/"))
+		}
+		return true
+	})
+	return keys, err
+}
+
+func (s *s3Store) Delete(key string) error {
+	_, err := s.client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(key)),
+	})
+	return err
+}
@@ -0,0 +1,50 @@
+// Package store provides a minimal object-store abstraction over S3, GCS,
+// and Azure Blob Storage, addressed by URL ("s3://bucket/prefix",
+// "gs://bucket/prefix", "azblob://container/prefix"), so the backup and
+// restore subsystems don't need to care which cloud they're talking to.
+package store
+
+import (
+	"errors"
+	"io"
+	"net/url"
+)
+
+// Store puts, gets, lists, and deletes objects under a configured
+// bucket/prefix.
+type Store interface {
+	// Put uploads the contents of r to key, relative to the store's
+	// configured prefix.
+	Put(key string, r io.Reader) error
+
+	// Get downloads the object at key. The caller must close it.
+	Get(key string) (io.ReadCloser, error)
+
+	// List returns the keys under prefix, relative to the store's own
+	// configured prefix, in lexical order.
+	List(prefix string) ([]string, error)
+
+	// Delete removes the object at key. It is not an error if key does
+	// not exist.
+	Delete(key string) error
+}
+
+// New returns the Store addressed by rawurl, e.g. "s3://bucket/prefix",
+// "gs://bucket/prefix", or "azblob://container/prefix".
+func New(rawurl string) (Store, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "s3":
+		return newS3Store(u)
+	case "gs":
+		return newGCSStore(u)
+	case "azblob":
+		return newAzblobStore(u)
+	default:
+		return nil, errors.New("store: unsupported scheme: " + u.Scheme)
+	}
+}
@@ -0,0 +1,17 @@
+package store
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+)
+
+// readAll buffers r fully; the S3 SDK needs a seekable body to compute
+// checksums and retry uploads.
+func readAll(r io.Reader) ([]byte, error) {
+	return ioutil.ReadAll(r)
+}
+
+func bytesReader(b []byte) io.ReadSeeker {
+	return bytes.NewReader(b)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"path/filepath"
+
+	"github.com/influxdb/influxdb/toml"
+)
+
+// Config represents the configuration format for the influxd binary.
+type Config struct {
+	Broker          BrokerConfig          `toml:"broker"`
+	Data            DataConfig            `toml:"data"`
+	Raft            RaftConfig            `toml:"raft"`
+	Logging         LoggingConfig         `toml:"logging"`
+	ContinuousQuery ContinuousQueryConfig `toml:"continuous_queries"`
+	Discovery       DiscoveryConfig       `toml:"discovery"`
+	TLS             TLSConfig             `toml:"tls"`
+	AutoBackup      AutoBackupConfig      `toml:"auto-backup"`
+	AutoRestore     AutoRestoreConfig     `toml:"auto-restore"`
+}
+
+// BrokerDir returns the data directory to use for the broker.
+func (c *Config) BrokerDir() string {
+	return filepath.Join(c.Data.Dir, "broker")
+}
+
+// BrokerConfig represents the configuration for the broker's raft-backed topics.
+type BrokerConfig struct {
+	TruncationInterval toml.Duration `toml:"truncation-interval"`
+	MaxTopicSize       int64         `toml:"max-topic-size"`
+	MaxSegmentSize     int64         `toml:"max-segment-size"`
+
+	// MaxRecoveryBacklog bounds how many sequence numbers behind a
+	// reconnecting peer may resume replay from; a peer that fell further
+	// behind than this jumps forward to currentSeq-MaxRecoveryBacklog
+	// instead of pinning unbounded segment data on disk. 0 means
+	// unlimited.
+	MaxRecoveryBacklog uint64 `toml:"max-recovery-backlog"`
+}
+
+// DataConfig represents the configuration for the data node.
+type DataConfig struct {
+	Dir                 string `toml:"dir"`
+	RetentionAutoCreate bool   `toml:"retention-auto-create"`
+}
+
+// RaftConfig represents the configuration for the raft consensus protocol.
+type RaftConfig struct {
+	ApplyInterval     toml.Duration `toml:"apply-interval"`
+	ElectionTimeout   toml.Duration `toml:"election-timeout"`
+	HeartbeatInterval toml.Duration `toml:"heartbeat-interval"`
+	ReconnectTimeout  toml.Duration `toml:"reconnect-timeout"`
+}
+
+// LoggingConfig represents the configuration for logging and tracing.
+type LoggingConfig struct {
+	RaftTracing  bool `toml:"raft-tracing-enabled"`
+	WriteTracing bool `toml:"write-tracing-enabled"`
+}
+
+// ContinuousQueryConfig represents the configuration for continuous query recomputation.
+type ContinuousQueryConfig struct {
+	RecomputePreviousN     int           `toml:"recompute-previous-n"`
+	RecomputeNoOlderThan   toml.Duration `toml:"recompute-no-older-than"`
+	ComputeRunsPerInterval int           `toml:"compute-runs-per-interval"`
+	ComputeNoMoreThan      toml.Duration `toml:"compute-no-more-than"`
+}
+
+// DiscoveryConfig represents the configuration for automatic cluster bootstrap
+// via a service discovery backend instead of a static `-join` list.
+type DiscoveryConfig struct {
+	Enabled   bool               `toml:"enabled"`
+	Backend   string             `toml:"backend"` // "consul", "etcd", or "dns"
+	Endpoints []string           `toml:"endpoints"`
+	Key       string             `toml:"key"`
+	TTL       toml.Duration      `toml:"ttl"`
+	TLS       DiscoveryTLSConfig `toml:"tls"`
+}
+
+// DiscoveryTLSConfig holds the TLS settings for talking to the discovery
+// backend. The consul and etcd backends apply Enabled and
+// InsecureSkipVerify only; CertFile, KeyFile and CAFile are not read.
+type DiscoveryTLSConfig struct {
+	Enabled            bool   `toml:"enabled"`
+	CertFile           string `toml:"cert"`
+	KeyFile            string `toml:"key"`
+	CAFile             string `toml:"ca"`
+	InsecureSkipVerify bool   `toml:"insecure-skip-verify"`
+}
+
+// TLSConfig represents the configuration for mutual TLS on the cluster
+// (Raft/messaging) and API listeners.
+type TLSConfig struct {
+	Enabled            bool   `toml:"enabled"`
+	CertFile           string `toml:"cert"`
+	KeyFile            string `toml:"key"`
+	CAFile             string `toml:"ca"`
+	InsecureSkipVerify bool   `toml:"insecure-skip-verify"`
+
+	// ClientAuth controls whether/how client certificates are verified:
+	// "none", "request", "require", "verify", or "require-and-verify".
+	ClientAuth string `toml:"client-auth"`
+
+	// NodeCertFile/NodeKeyFile, if set, are used for cluster (Raft and
+	// messaging) traffic instead of CertFile/KeyFile, so operators can
+	// give intra-cluster traffic a distinct identity from the API.
+	NodeCertFile string `toml:"node-cert"`
+	NodeKeyFile  string `toml:"node-key"`
+}
+
+// AutoBackupConfig represents the configuration for periodic snapshot
+// uploads of the broker's raft log and the data node's shards to an
+// object store.
+type AutoBackupConfig struct {
+	Enabled  bool          `toml:"enabled"`
+	URL      string        `toml:"url"` // e.g. "s3://bucket/prefix", "gs://bucket/prefix", "azblob://container/prefix"
+	Interval toml.Duration `toml:"interval"`
+	Retain   int           `toml:"retain"` // generations to keep; 0 means unlimited
+}
+
+// AutoRestoreConfig represents the configuration for restoring a fresh
+// node's data directory from the latest uploaded snapshot before it
+// attempts to join the cluster.
+type AutoRestoreConfig struct {
+	Enabled bool   `toml:"enabled"`
+	URL     string `toml:"url"`
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/influxdb/influxdb"
+	"github.com/influxdb/influxdb/messaging"
+	"github.com/influxdb/influxdb/raft"
+)
+
+// Handler is the top-level HTTP handler shared by a node's cluster and API
+// listeners. Before a request reaches the node's normal query/write
+// routing (Next), it checks whether this node can actually serve it.
+type Handler struct {
+	Broker     *influxdb.Broker
+	Log        *raft.Log
+	ClusterFSM *messaging.ClusterFSM
+
+	// Node is used to service the admin /remove endpoint.
+	Node *Node
+
+	// Next serves any request this node doesn't redirect itself, i.e. the
+	// node's regular query/write API routing.
+	Next http.Handler
+}
+
+// ServeHTTP redirects requests that only the Raft leader can serve
+// (writes, and node removal) to the current leader's HTTP API address
+// when received on a follower, so clients don't need to know cluster
+// topology up front, and services the admin node-removal and recovery
+// endpoints. Everything else is passed through to Next.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == "GET" && r.URL.Path == "/recovery" {
+		h.serveRecovery(w, r)
+		return
+	}
+
+	if needsLeader(r) && h.Log != nil && !h.Log.IsLeader() {
+		if h.redirectToLeader(w, r) {
+			return
+		}
+	}
+
+	if r.Method == "DELETE" && r.URL.Path == "/remove" {
+		h.serveRemove(w, r)
+		return
+	}
+	h.Next.ServeHTTP(w, r)
+}
+
+// serveRemove handles "DELETE /remove", decommissioning the node named in
+// the request body from both the broker's raft configuration and the
+// data-node membership list, so the leader stops trying to replicate to
+// it.
+func (h *Handler) serveRemove(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID uint64 `json:"id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.ID == 0 {
+		http.Error(w, "id is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Node.RemoveNode(req.ID); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// serveRecovery handles "GET /recovery", reporting how far behind each
+// peer currently being (or last) replayed to is, so operators can see
+// per-peer lag.
+func (h *Handler) serveRecovery(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.Node.RecoveryProgress())
+}
+
+// needsLeader reports whether r must be served by the current Raft
+// leader: writes, because only the leader may propose to the raft log,
+// and node removal, because raftLog.RemovePeer is itself a leader-only
+// configuration change.
+func needsLeader(r *http.Request) bool {
+	if r.Method == "POST" && strings.HasPrefix(r.URL.Path, "/write") {
+		return true
+	}
+	if r.Method == "DELETE" && r.URL.Path == "/remove" {
+		return true
+	}
+	return false
+}
+
+// redirectToLeader 307-redirects r to the current Raft leader's HTTP API
+// address, as published in NodeMeta. It reports false, leaving the
+// request unhandled, if the leader or its address isn't known yet.
+func (h *Handler) redirectToLeader(w http.ResponseWriter, r *http.Request) bool {
+	if h.ClusterFSM == nil {
+		return false
+	}
+
+	leaderID := h.Log.Leader()
+	if leaderID == 0 {
+		return false
+	}
+
+	meta := h.ClusterFSM.NodeMeta(leaderID)
+	addr := meta[messaging.NodeMetaAPIAddr]
+	if addr == "" {
+		return false
+	}
+
+	u := *r.URL
+	u.Scheme = "http"
+	if meta[messaging.NodeMetaAPITLS] == "true" {
+		u.Scheme = "https"
+	}
+	u.Host = addr
+
+	http.Redirect(w, r, u.String(), http.StatusTemporaryRedirect)
+	return true
+}
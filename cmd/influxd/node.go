@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net"
 	"net/http"
@@ -12,10 +13,16 @@ import (
 
 	"github.com/influxdb/influxdb"
 	"github.com/influxdb/influxdb/admin"
+	"github.com/influxdb/influxdb/auto/backup"
+	"github.com/influxdb/influxdb/auto/restore"
+	"github.com/influxdb/influxdb/auto/store"
+	"github.com/influxdb/influxdb/disco"
 	"github.com/influxdb/influxdb/graphite"
 	"github.com/influxdb/influxdb/messaging"
 	"github.com/influxdb/influxdb/opentsdb"
 	"github.com/influxdb/influxdb/raft"
+	"github.com/influxdb/influxdb/recovery"
+	"github.com/influxdb/influxdb/rtls"
 )
 
 // Node represent a member of a cluster.  A Node could serve as broker, a data node
@@ -33,6 +40,40 @@ type Node struct {
 	apiListener     net.Listener      // The API TCP listener
 	GraphiteServers []graphite.Server // The Graphite Servers
 	OpenTSDBServer  *opentsdb.Server  // The OpenTSDB Server
+
+	disco     disco.Backend // Service discovery backend, if configured
+	discoDone chan struct{} // Closed to stop the leader-registration refresh loop
+
+	nodeMetaDone chan struct{} // Closed to stop the node-meta leadership watch
+
+	clusterFSM *messaging.ClusterFSM // Tracks per-node metadata (e.g. API addr) alongside broker state
+
+	apiTLS     *rtls.Manager // Serves/rotates the cert used by the API listener
+	clusterTLS *rtls.Manager // Serves/rotates the cert used by the cluster (Raft/messaging) listener
+
+	brokerBackup *backup.Uploader // Periodically uploads the broker dir, if auto-backup is enabled
+	dataBackup   *backup.Uploader // Periodically uploads the data dir, if auto-backup is enabled
+
+	replayer *recovery.Replayer // Replays buffered writes to peers that reconnect after an outage
+}
+
+// RecoveryProgress returns the current per-peer replay progress, for the
+// admin recovery endpoint. It returns nil if recovery replay isn't
+// running (e.g. this node isn't hosting a broker).
+func (s *Node) RecoveryProgress() []*recovery.Progress {
+	if s.replayer == nil {
+		return nil
+	}
+	return s.replayer.Progress()
+}
+
+// PeerAPIAddr returns the HTTP API address nodeID last published about
+// itself, or an empty string if it hasn't published one (or isn't known).
+func (s *Node) PeerAPIAddr(nodeID uint64) string {
+	if s.clusterFSM == nil {
+		return ""
+	}
+	return s.clusterFSM.NodeMeta(nodeID)[messaging.NodeMetaAPIAddr]
 }
 
 func NewNodeWithConfig(config *Config) *Node {
@@ -53,8 +94,12 @@ func (s *Node) ClusterURL() *url.URL {
 	}
 
 	h := net.JoinHostPort(s.hostname, p)
+	scheme := "http"
+	if s.config.TLS.Enabled {
+		scheme = "https"
+	}
 	return &url.URL{
-		Scheme: "http",
+		Scheme: scheme,
 		Host:   h,
 	}
 }
@@ -79,6 +124,16 @@ func (s *Node) openBroker(brokerURLs []url.URL, h *Handler) {
 	b.Log = l
 	s.raftLog = l
 
+	// If cluster TLS is enabled, give the raft log's HTTP transport a
+	// matching cert so peers mutually authenticate over Raft RPCs.
+	if s.config.TLS.Enabled {
+		mgr, err := s.tlsManagerFor("Cluster")
+		if err != nil {
+			log.Fatalf("raft: tls: %s", err)
+		}
+		l.Transport = mgr.Transport()
+	}
+
 	// Create Raft clock.
 	clk := raft.NewClock()
 	clk.ApplyInterval = time.Duration(s.config.Raft.ApplyInterval)
@@ -87,14 +142,30 @@ func (s *Node) openBroker(brokerURLs []url.URL, h *Handler) {
 	clk.ReconnectTimeout = time.Duration(s.config.Raft.ReconnectTimeout)
 	l.Clock = clk
 
+	// If this is a fresh node and auto-restore is enabled, warm the
+	// broker directory from the latest snapshot before opening it.
+	if err := s.autoRestore("broker", path, s.config.AutoRestore.URL); err != nil {
+		log.Fatalf("auto-restore: %s", err)
+	}
+
 	// Open broker so it can feed last index data to the log.
 	if err := b.Open(path); err != nil {
 		log.Fatalf("failed to open broker at %s : %s", path, err)
 	}
 	log.Printf("broker opened at %s", path)
 
-	// Attach the broker as the finite state machine of the raft log.
-	l.FSM = &messaging.RaftFSM{Broker: b}
+	// Start periodically uploading snapshots of the broker directory, if
+	// configured. The uploader itself only runs while this node is the
+	// Raft leader.
+	if err := s.startBackup("broker", path, &s.brokerBackup); err != nil {
+		log.Printf("auto-backup: %s", err)
+	}
+
+	// Attach the broker as the finite state machine of the raft log. The
+	// ClusterFSM wrapper additionally tracks the per-node metadata (API
+	// address, etc.) peers publish via SetNodeMeta.
+	s.clusterFSM = messaging.NewClusterFSM(&messaging.RaftFSM{Broker: b})
+	l.FSM = s.clusterFSM
 
 	// Open raft log inside broker directory.
 	if err := l.Open(filepath.Join(path, "raft")); err != nil {
@@ -104,25 +175,293 @@ func (s *Node) openBroker(brokerURLs []url.URL, h *Handler) {
 	// Attach broker and log to handler.
 	h.Broker = b
 	h.Log = l
+	h.ClusterFSM = s.clusterFSM
+	h.Node = s
 
 	// Checks to see if the raft index is 0.  If it's 0, it might be the first
 	// node in the cluster and must initialize or join
 	index, _ := l.LastLogIndexTerm()
 	if index == 0 {
+		joined := false
+
+		// If service discovery is enabled, prefer it over a static join list:
+		// it tells us either who to join, or that we're free to claim the
+		// leader key ourselves.
+		if s.config.Discovery.Enabled {
+			joined = s.openDiscoveredBroker(l, *u)
+		}
+
 		// If we have join URLs, then attemp to join the cluster
-		if len(brokerURLs) > 0 {
+		if !joined && len(brokerURLs) > 0 {
 			s.joinLog(l, brokerURLs)
-			return
+			joined = true
 		}
 
-		if err := l.Initialize(); err != nil {
-			log.Fatalf("initialize raft log: %s", err)
-		}
+		if !joined {
+			if err := l.Initialize(); err != nil {
+				log.Fatalf("initialize raft log: %s", err)
+			}
 
-		u := b.Broker.URL()
-		log.Printf("initialized broker: %s\n", (&u).String())
+			u := b.Broker.URL()
+			log.Printf("initialized broker: %s\n", (&u).String())
+		}
 	} else {
 		log.Printf("broker already member of cluster.  Using existing state and ignoring join URLs")
+
+		// A restarting existing member skips openDiscoveredBroker, which is
+		// otherwise the only place s.disco gets set. Without this, a node
+		// that's since been elected leader would never call disco.Update,
+		// leaving the shared discovery key stale after a failover.
+		if s.config.Discovery.Enabled {
+			s.openDiscoveryBackend()
+		}
+	}
+
+	// These must run regardless of which branch above brought the raft log
+	// up — a node that bootstrapped via discovery or joined an existing
+	// cluster needs the leader-registration refresh and recovery replay
+	// just as much as one that called Initialize directly.
+	if s.disco != nil {
+		s.startDiscoveryRefresh(*u)
+	}
+	s.startNodeMetaRefresh()
+	s.startRecovery()
+}
+
+// startNodeMetaRefresh watches for this node acquiring Raft leadership and
+// re-publishes its node meta when that happens. publishNodeMeta's call at
+// API-listener-open time only succeeds if this node is already the
+// leader; a node that starts (or stays) a follower needs this to publish
+// once it's actually promoted, so that redirectToLeader can resolve its
+// API address after a failover.
+func (s *Node) startNodeMetaRefresh() {
+	interval := time.Duration(s.config.Raft.ElectionTimeout)
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	done := make(chan struct{})
+	s.nodeMetaDone = done
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var wasLeader bool
+		for {
+			select {
+			case <-ticker.C:
+				isLeader := s.raftLog != nil && s.raftLog.IsLeader()
+				if isLeader && !wasLeader {
+					s.publishNodeMeta()
+				}
+				wasLeader = isLeader
+			case <-done:
+				return
+			}
+		}
+	}()
+}
+
+// startRecovery watches for peers reconnecting after an outage and
+// replays any writes they missed while unreachable, so a flaky peer
+// doesn't have to fall fully out of sync and force a full re-join.
+// Replay is resumable and bounded by Config.Broker.MaxRecoveryBacklog.
+//
+// Replay operates in the broker's per-topic message sequence space, not
+// the raft log's index space: the raft log also advances for cluster
+// membership changes that never touch a topic, so it can't be used to
+// bound a topic backlog, and evt.LastSeq (the peer's own last-acked
+// sequence), not the raft log, is what tells us where to resume from.
+func (s *Node) startRecovery() {
+	dir := filepath.Join(s.config.BrokerDir(), "recovery")
+	s.replayer = recovery.NewReplayer(s.Broker, dir, s.config.Broker.MaxRecoveryBacklog)
+
+	go func() {
+		for evt := range s.raftLog.PeerEvents() {
+			if !evt.Connected {
+				s.replayer.Stop(evt.NodeID)
+				continue
+			}
+
+			currentSeq, err := s.Broker.TopicHeadIndex(evt.NodeID)
+			if err != nil {
+				log.Printf("recovery: failed to read topic head for node %d: %s", evt.NodeID, err)
+				continue
+			}
+			s.replayer.Start(evt.NodeID, evt.LastSeq, currentSeq, func(msg *messaging.Message) error {
+				return s.raftLog.Send(evt.NodeID, msg)
+			})
+		}
+	}()
+}
+
+// openDiscoveryBackend creates this node's disco.Backend and retains it on
+// s.disco, so later code (startDiscoveryRefresh, and a leader election
+// that happens after this call) can keep the shared discovery key
+// current. It's shared by the fresh-bootstrap path (openDiscoveredBroker)
+// and by a node restarting as an existing cluster member, which otherwise
+// never sets s.disco at all.
+func (s *Node) openDiscoveryBackend() (disco.Backend, bool) {
+	d, err := disco.New(s.discoveryConfig())
+	if err != nil {
+		log.Printf("disco: failed to create %s backend: %s", s.config.Discovery.Backend, err)
+		return nil, false
+	}
+	s.disco = d
+	return d, true
+}
+
+// openDiscoveredBroker consults the configured discovery backend to either
+// join an existing leader or atomically register this node as the first
+// member of the cluster. It returns true if it fully handled bringing up
+// the raft log (join or initialize), false if the caller should fall back
+// to the static join list / plain Initialize.
+func (s *Node) openDiscoveredBroker(l *raft.Log, u url.URL) bool {
+	d, ok := s.openDiscoveryBackend()
+	if !ok {
+		return false
+	}
+
+	leader, err := d.Leader()
+	if err != nil && err != disco.ErrNoLeader {
+		log.Printf("disco: failed to look up leader: %s", err)
+		return false
+	}
+
+	if leader != "" {
+		leaderURL, err := url.Parse(leader)
+		if err != nil {
+			log.Printf("disco: invalid leader URL %q: %s", leader, err)
+			return false
+		}
+		s.joinLog(l, []url.URL{*leaderURL})
+		return true
+	}
+
+	// No leader registered yet. Try to claim the key ourselves.
+	ok, err := d.Register(u.String())
+	if err != nil {
+		log.Printf("disco: failed to register as leader: %s", err)
+		return false
+	}
+	if !ok {
+		// Another node won the race to register; join it instead.
+		leader, err = d.Leader()
+		if err != nil || leader == "" {
+			log.Printf("disco: lost leader registration race but found no leader: %s", err)
+			return false
+		}
+		leaderURL, err := url.Parse(leader)
+		if err != nil {
+			log.Printf("disco: invalid leader URL %q: %s", leader, err)
+			return false
+		}
+		s.joinLog(l, []url.URL{*leaderURL})
+		return true
+	}
+
+	if err := l.Initialize(); err != nil {
+		log.Fatalf("initialize raft log: %s", err)
+	}
+	log.Printf("disco: registered %s as initial cluster leader", u.String())
+	return true
+}
+
+// startDiscoveryRefresh periodically re-registers this node's URL with the
+// discovery backend for as long as it remains the Raft leader, so that a
+// failover updates the shared key to point at the new leader.
+func (s *Node) startDiscoveryRefresh(u url.URL) {
+	interval := time.Duration(s.config.Discovery.TTL) / 2
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	done := make(chan struct{})
+	s.discoDone = done
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if s.raftLog == nil || !s.raftLog.IsLeader() {
+					continue
+				}
+				if err := s.disco.Update(u.String()); err != nil {
+					log.Printf("disco: failed to refresh leader registration: %s", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+}
+
+// autoRestore downloads the latest snapshot named name into dir before
+// it's opened, if auto-restore is enabled against rawurl and dir doesn't
+// already hold data (ignoring the entries named in except), so a
+// wiped/replaced node comes up warm instead of empty.
+func (s *Node) autoRestore(name, dir, rawurl string, except ...string) error {
+	if !s.config.AutoRestore.Enabled {
+		return nil
+	}
+
+	st, err := store.New(rawurl)
+	if err != nil {
+		return err
+	}
+
+	ok, err := restore.IfEmpty(st, name, dir, except...)
+	if err != nil {
+		return err
+	}
+	if ok {
+		log.Printf("auto-restore: restored %s from latest snapshot", name)
+	}
+	return nil
+}
+
+// startBackup begins periodically snapshotting dir to the configured
+// object store while this node is the Raft leader, storing the resulting
+// Uploader in *dst so Close can stop it later. It's a no-op if auto-backup
+// isn't enabled.
+func (s *Node) startBackup(name, dir string, dst **backup.Uploader) error {
+	if !s.config.AutoBackup.Enabled {
+		return nil
+	}
+
+	st, err := store.New(s.config.AutoBackup.URL)
+	if err != nil {
+		return err
+	}
+
+	u := backup.NewUploader(
+		st,
+		[]backup.Source{{Name: name, Dir: dir}},
+		time.Duration(s.config.AutoBackup.Interval),
+		s.config.AutoBackup.Retain,
+		func() bool { return s.raftLog != nil && s.raftLog.IsLeader() },
+	)
+	u.Open()
+	*dst = u
+	return nil
+}
+
+// discoveryConfig converts the server's [discovery] config section into a
+// disco.Config for the selected backend.
+func (s *Node) discoveryConfig() disco.Config {
+	d := s.config.Discovery
+	return disco.Config{
+		Backend:               d.Backend,
+		Endpoints:             d.Endpoints,
+		Key:                   d.Key,
+		TTL:                   time.Duration(d.TTL),
+		TLSEnabled:            d.TLS.Enabled,
+		TLSCertFile:           d.TLS.CertFile,
+		TLSKeyFile:            d.TLS.KeyFile,
+		TLSCAFile:             d.TLS.CAFile,
+		TLSInsecureSkipVerify: d.TLS.InsecureSkipVerify,
 	}
 }
 
@@ -142,8 +481,79 @@ func (n *Node) joinLog(l *raft.Log, brokerURLs []url.URL) {
 	log.Fatalf("join: failed to connect raft log to any specified server")
 }
 
+// RemoveNode removes nodeID from both the broker's raft configuration and
+// the data-node membership list, so the leader stops trying to replicate
+// to it. raftLog.RemovePeer is a leader-only Raft configuration change,
+// so this must be called on (or redirected to) the current leader;
+// Handler.ServeHTTP takes care of that for the HTTP endpoint.
+func (s *Node) RemoveNode(nodeID uint64) error {
+	if s.raftLog != nil {
+		if err := s.raftLog.RemovePeer(nodeID); err != nil {
+			return err
+		}
+	}
+	if s.Broker != nil {
+		if err := s.Broker.RemoveDataNode(nodeID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Decommission gracefully removes this node from the cluster before it's
+// torn down: it transfers away Raft leadership if this node holds it,
+// waits for in-flight writes to drain, then submits its own removal
+// command so peers stop trying to reach it. Callers should follow it with
+// Close to actually release listeners and local resources.
+func (s *Node) Decommission(ctx context.Context) error {
+	if s.raftLog != nil && s.raftLog.IsLeader() {
+		if err := s.raftLog.TransferLeadership(); err != nil {
+			return err
+		}
+	}
+
+	if s.DataNode != nil {
+		if err := s.DataNode.Drain(ctx); err != nil {
+			return err
+		}
+	}
+
+	var nodeID uint64
+	if s.raftLog != nil {
+		nodeID = s.raftLog.ID()
+	} else if s.DataNode != nil {
+		nodeID = s.DataNode.ID()
+	}
+	if nodeID == 0 {
+		return nil
+	}
+	return s.RemoveNode(nodeID)
+}
+
 // Close stops all listeners and services on the node
 func (s *Node) Close() error {
+	if s.brokerBackup != nil {
+		s.brokerBackup.Close()
+	}
+	if s.dataBackup != nil {
+		s.dataBackup.Close()
+	}
+
+	if s.nodeMetaDone != nil {
+		close(s.nodeMetaDone)
+		s.nodeMetaDone = nil
+	}
+
+	if s.discoDone != nil {
+		close(s.discoDone)
+		s.discoDone = nil
+	}
+	if s.disco != nil {
+		if err := s.disco.Close(); err != nil {
+			return err
+		}
+	}
+
 	if err := s.closeClusterListener(); err != nil {
 		return err
 	}
@@ -196,6 +606,17 @@ func (s *Node) openServer(joinURLs []url.URL) *influxdb.Server {
 	c := influxdb.NewMessagingClient(*s.ClusterURL())
 	c.SetURLs(joinURLs)
 
+	// If cluster TLS is enabled, give the messaging client a matching
+	// transport so intra-cluster traffic is encrypted and mutually
+	// authenticated, just like the raft log's transport.
+	if s.config.TLS.Enabled {
+		mgr, err := s.tlsManagerFor("Cluster")
+		if err != nil {
+			log.Fatalf("messaging client: tls: %s", err)
+		}
+		c.SetTransport(mgr.Transport())
+	}
+
 	if err := c.Open(filepath.Join(s.config.Data.Dir, messagingClientFile)); err != nil {
 		log.Fatalf("messaging client error: %s", err)
 	}
@@ -217,12 +638,28 @@ func (s *Node) openServer(joinURLs []url.URL) *influxdb.Server {
 	n.Version = version
 	n.CommitHash = commit
 
+	// If this is a fresh node and auto-restore is enabled, warm the data
+	// directory from the latest snapshot before opening it. On a combined
+	// broker+data node, openBroker has already created Data.Dir/broker by
+	// this point, so ignore it when deciding whether Data.Dir is "empty" -
+	// otherwise restore would always see it as already populated and skip.
+	if err := s.autoRestore("data", s.config.Data.Dir, s.config.AutoRestore.URL, "broker"); err != nil {
+		log.Fatalf("auto-restore: %s", err)
+	}
+
 	// Open server with data directory and broker client.
 	if err := n.Open(s.config.Data.Dir, c); err != nil {
 		log.Fatalf("failed to open data node: %v", err.Error())
 	}
 	log.Printf("data node(%d) opened at %s", n.ID(), s.config.Data.Dir)
 
+	// Start periodically uploading snapshots of the data directory, if
+	// configured. The uploader itself only runs while this node is the
+	// Raft leader.
+	if err := s.startBackup("data", s.config.Data.Dir, &s.dataBackup); err != nil {
+		log.Printf("auto-backup: %s", err)
+	}
+
 	// Give brokers time to elect a leader if entire cluster is being restarted.
 	time.Sleep(1 * time.Second)
 
@@ -271,6 +708,15 @@ func (s *Node) openAdminServer(port int) error {
 	// Start the admin interface on the default port
 	addr := net.JoinHostPort("", strconv.Itoa(port))
 	s.adminServer = admin.NewServer(addr)
+
+	if s.config.TLS.Enabled {
+		mgr, err := s.tlsManagerFor("API")
+		if err != nil {
+			return err
+		}
+		s.adminServer.TLSConfig = mgr.TLSConfig()
+	}
+
 	return s.adminServer.ListenAndServe()
 }
 
@@ -287,6 +733,15 @@ func (s *Node) openListener(desc, addr string, h http.Handler) (net.Listener, er
 	if err != nil {
 		return nil, err
 	}
+
+	if s.config.TLS.Enabled {
+		mgr, err := s.tlsManagerFor(desc)
+		if err != nil {
+			return nil, err
+		}
+		listener = mgr.Listen(listener)
+	}
+
 	go func() {
 		err := http.Serve(listener, h)
 
@@ -303,15 +758,102 @@ func (s *Node) openListener(desc, addr string, h http.Handler) (net.Listener, er
 
 }
 
+// tlsManagerFor returns the rtls.Manager used to terminate TLS on the
+// named listener, creating it on first use. Cluster traffic uses the
+// node-cert/node-key pair if configured, so operators can give
+// intra-cluster traffic a distinct identity from the client-facing API.
+func (s *Node) tlsManagerFor(desc string) (*rtls.Manager, error) {
+	t := s.config.TLS
+
+	clientAuth, err := rtls.ParseClientAuth(t.ClientAuth)
+	if err != nil {
+		return nil, err
+	}
+
+	if desc == "Cluster" {
+		if s.clusterTLS == nil {
+			certFile, keyFile := t.NodeCertFile, t.NodeKeyFile
+			if certFile == "" {
+				certFile, keyFile = t.CertFile, t.KeyFile
+			}
+			s.clusterTLS, err = rtls.NewManager(rtls.Config{
+				CertFile:           certFile,
+				KeyFile:            keyFile,
+				CAFile:             t.CAFile,
+				InsecureSkipVerify: t.InsecureSkipVerify,
+				ClientAuth:         clientAuth,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		return s.clusterTLS, nil
+	}
+
+	if s.apiTLS == nil {
+		s.apiTLS, err = rtls.NewManager(rtls.Config{
+			CertFile:           t.CertFile,
+			KeyFile:            t.KeyFile,
+			CAFile:             t.CAFile,
+			InsecureSkipVerify: t.InsecureSkipVerify,
+			ClientAuth:         clientAuth,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.apiTLS, nil
+}
+
 func (s *Node) openAPIListener(addr string, h http.Handler) error {
 	var err error
 	s.apiListener, err = s.openListener("API", addr, h)
 	if err != nil {
 		return err
 	}
+	s.publishNodeMeta()
 	return nil
 }
 
+// publishNodeMeta submits this node's current HTTP API address (and
+// related metadata) as a SetNodeMeta command through the raft log, so
+// that every node in the cluster converges on how to reach it. It's
+// called whenever the API listener is opened (openAPIListener), and
+// again whenever this node acquires Raft
+// leadership (see startNodeMetaRefresh), since Apply only succeeds on
+// the leader: a node that starts as a follower logs a failed publish at
+// startup and must retry once it's actually able to propose.
+func (s *Node) publishNodeMeta() {
+	if s.raftLog == nil || s.clusterFSM == nil || s.apiListener == nil {
+		return
+	}
+
+	// s.apiListener.Addr() is the bound address, typically a wildcard
+	// like ":8086" or "[::]:8086" — not something a peer can dial.
+	// Advertise it the same way ClusterURL does: our configured hostname
+	// plus whatever port we actually ended up listening on.
+	_, port, err := net.SplitHostPort(s.apiListener.Addr().String())
+	if err != nil {
+		log.Printf("node meta: failed to determine api addr: %s", err)
+		return
+	}
+
+	meta := messaging.NodeMeta{
+		messaging.NodeMetaAPIAddr: net.JoinHostPort(s.hostname, port),
+		messaging.NodeMetaAPITLS:  strconv.FormatBool(s.config.TLS.Enabled),
+		messaging.NodeMetaVersion: version,
+	}
+
+	cmd, err := messaging.EncodeSetNodeMeta(s.raftLog.ID(), meta)
+	if err != nil {
+		log.Printf("node meta: failed to encode: %s", err)
+		return
+	}
+	if _, err := s.raftLog.Apply(cmd); err != nil {
+		log.Printf("node meta: failed to publish: %s", err)
+	}
+}
+
 func (s *Node) closeAPIListener() error {
 	var err error
 	if s.apiListener != nil {
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/influxdb/influxdb/toml"
+)
+
+// newTestNode brings up a single node's broker (and the raft log backing
+// it) rooted at dir, joining joinURLs if non-empty or bootstrapping a new
+// cluster otherwise, wired to its own Handler the way main() does.
+func newTestNode(t *testing.T, dir string, joinURLs []url.URL) *Node {
+	t.Helper()
+
+	cfg := &Config{}
+	cfg.Data.Dir = dir
+	cfg.Raft.ElectionTimeout = toml.Duration(50 * time.Millisecond)
+	cfg.Raft.HeartbeatInterval = toml.Duration(10 * time.Millisecond)
+	cfg.Raft.ApplyInterval = toml.Duration(10 * time.Millisecond)
+	cfg.Raft.ReconnectTimeout = toml.Duration(10 * time.Millisecond)
+
+	n := NewNodeWithConfig(cfg)
+	n.hostname = "127.0.0.1"
+
+	h := &Handler{}
+	if err := n.openClusterListener("127.0.0.1:0", h); err != nil {
+		t.Fatalf("open cluster listener: %s", err)
+	}
+
+	n.openBroker(joinURLs, h)
+	t.Cleanup(func() { n.Close() })
+	return n
+}
+
+// TestNode_RemoveNode_ThreeNodeCluster bootstraps a 3-node broker cluster,
+// removes one node by ID from the leader, and verifies that the leader's
+// raft configuration no longer lists it as a peer.
+func TestNode_RemoveNode_ThreeNodeCluster(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influxd-remove-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	n1 := newTestNode(t, filepath.Join(dir, "1"), nil)
+	n2 := newTestNode(t, filepath.Join(dir, "2"), []url.URL{*n1.ClusterURL()})
+	n3 := newTestNode(t, filepath.Join(dir, "3"), []url.URL{*n1.ClusterURL()})
+
+	// Give the cluster a moment to settle: all three raft logs connected
+	// and a leader elected.
+	time.Sleep(200 * time.Millisecond)
+
+	removedID := n3.raftLog.ID()
+
+	leader := n1
+	if !leader.raftLog.IsLeader() {
+		leader = n2
+	}
+
+	if !containsPeer(leader.raftLog.Peers(), removedID) {
+		t.Fatalf("node %d not a peer before removal", removedID)
+	}
+
+	if err := leader.RemoveNode(removedID); err != nil {
+		t.Fatalf("RemoveNode: %s", err)
+	}
+
+	// The leader's raft configuration should drop removedID shortly after
+	// removal. RecoveryProgress alone can't distinguish this: it's empty
+	// in a quiescent cluster whether or not removal actually took effect.
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if !containsPeer(leader.raftLog.Peers(), removedID) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("node %d still a peer after removal", removedID)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func containsPeer(peers []uint64, id uint64) bool {
+	for _, p := range peers {
+		if p == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package disco
+
+import (
+	"crypto/tls"
+	"net/http"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// consulBackend discovers and registers the cluster leader using a single
+// key in Consul's KV store, guarded by Consul's check-and-set semantics.
+type consulBackend struct {
+	client *api.Client
+	key    string
+	ttl    int // seconds, from cfg.TTL; the key is written without a session, so it does not expire
+}
+
+func newConsulBackend(cfg Config) (Backend, error) {
+	conf := api.DefaultConfig()
+	if len(cfg.Endpoints) > 0 {
+		conf.Address = cfg.Endpoints[0]
+	}
+	if cfg.TLSEnabled {
+		conf.HttpClient.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: cfg.TLSInsecureSkipVerify},
+		}
+	}
+
+	client, err := api.NewClient(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return &consulBackend{
+		client: client,
+		key:    cfg.Key,
+		ttl:    int(cfg.TTL.Seconds()),
+	}, nil
+}
+
+func (b *consulBackend) Leader() (string, error) {
+	kv, _, err := b.client.KV().Get(b.key, nil)
+	if err != nil {
+		return "", err
+	}
+	if kv == nil {
+		return "", nil
+	}
+	if len(kv.Value) == 0 {
+		return "", ErrNoLeader
+	}
+	return string(kv.Value), nil
+}
+
+// Register attempts a CAS write against the key, succeeding only when the
+// key does not already exist (ModifyIndex == 0). This is how the first node
+// in a cluster claims the leader key atomically, even if several nodes
+// start up at once.
+func (b *consulBackend) Register(url string) (bool, error) {
+	pair := &api.KVPair{
+		Key:         b.key,
+		Value:       []byte(url),
+		ModifyIndex: 0,
+	}
+	ok, _, err := b.client.KV().CAS(pair, nil)
+	return ok, err
+}
+
+func (b *consulBackend) Update(url string) error {
+	_, err := b.client.KV().Put(&api.KVPair{Key: b.key, Value: []byte(url)}, nil)
+	return err
+}
+
+func (b *consulBackend) Close() error { return nil }
@@ -0,0 +1,67 @@
+// Package disco provides pluggable service-discovery backends that a
+// joining broker node can consult on startup to find an existing cluster
+// leader, or to atomically register itself as the first node in a new
+// cluster. This lets operators bootstrap a cluster without hard-coding a
+// static list of peer URLs in their orchestrator (Kubernetes, Nomad, etc).
+package disco
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNoLeader is returned by Backend.Leader when the discovery key exists
+// but does not yet reference a reachable leader.
+var ErrNoLeader = errors.New("disco: no leader registered")
+
+// Backend is implemented by each discovery mechanism (Consul, etcd, DNS).
+type Backend interface {
+	// Leader returns the cluster URL of the currently registered leader.
+	// It returns ErrNoLeader if the key is present but empty, and a nil
+	// error with an empty string if the key does not exist at all.
+	Leader() (string, error)
+
+	// Register atomically claims the discovery key for url if, and only
+	// if, it is currently unset. It returns true if this node became the
+	// registered leader.
+	Register(url string) (bool, error)
+
+	// Update overwrites the discovery key with url. It is called
+	// periodically by the current Raft leader so that a failover updates
+	// the shared key to point at the new leader. The key is written without
+	// an expiry: it keeps the last leader's URL until another node
+	// overwrites it.
+	Update(url string) error
+
+	// Close releases any resources held by the backend.
+	Close() error
+}
+
+// Config describes how to reach and use a discovery backend. It mirrors
+// the `[discovery]` section of the server config.
+type Config struct {
+	Backend   string // "consul", "etcd", or "dns"
+	Endpoints []string
+	Key       string
+	TTL       time.Duration
+
+	TLSEnabled            bool
+	TLSCertFile           string
+	TLSKeyFile            string
+	TLSCAFile             string
+	TLSInsecureSkipVerify bool
+}
+
+// New returns the Backend named by cfg.Backend, configured with cfg.
+func New(cfg Config) (Backend, error) {
+	switch cfg.Backend {
+	case "consul":
+		return newConsulBackend(cfg)
+	case "etcd":
+		return newEtcdBackend(cfg)
+	case "dns":
+		return newDNSBackend(cfg)
+	default:
+		return nil, errors.New("disco: unknown backend: " + cfg.Backend)
+	}
+}
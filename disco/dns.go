@@ -0,0 +1,53 @@
+package disco
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+)
+
+// dnsBackend discovers existing cluster members via a DNS SRV lookup,
+// typically backed by a Kubernetes headless service or similar. DNS is
+// read-only from this node's point of view: there is no key to register,
+// so Register always reports that it did not become the leader and the
+// caller falls back to treating itself as the first node.
+type dnsBackend struct {
+	name string // SRV record name to look up, e.g. "_cluster._tcp.influxdb.default.svc"
+}
+
+func newDNSBackend(cfg Config) (Backend, error) {
+	return &dnsBackend{name: cfg.Key}, nil
+}
+
+// Leader returns the cluster URL of the first SRV target returned by the
+// lookup; targets are not health-checked. Since plain DNS has no concept
+// of "the leader", this just returns an existing member for the new node
+// to join, which is not necessarily the leader.
+func (b *dnsBackend) Leader() (string, error) {
+	_, srvs, err := net.LookupSRV("", "", b.name)
+	if err != nil {
+		if _, ok := err.(*net.DNSError); ok {
+			return "", nil
+		}
+		return "", err
+	}
+	if len(srvs) == 0 {
+		return "", nil
+	}
+
+	target := srvs[0]
+	u := url.URL{
+		Scheme: "http",
+		Host:   net.JoinHostPort(target.Target, strconv.Itoa(int(target.Port))),
+	}
+	return u.String(), nil
+}
+
+// Register is a no-op: DNS records are managed by the orchestrator, not by
+// this process.
+func (b *dnsBackend) Register(url string) (bool, error) { return false, nil }
+
+// Update is a no-op for the same reason Register is.
+func (b *dnsBackend) Update(url string) error { return nil }
+
+func (b *dnsBackend) Close() error { return nil }
@@ -0,0 +1,72 @@
+package disco
+
+import (
+	"crypto/tls"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/coreos/etcd/clientv3"
+)
+
+// etcdBackend discovers and registers the cluster leader using a single key
+// in etcd, guarded by an etcd transaction that only succeeds when the key's
+// create revision is zero (i.e. the key doesn't exist yet).
+type etcdBackend struct {
+	client *clientv3.Client
+	key    string
+}
+
+func newEtcdBackend(cfg Config) (Backend, error) {
+	ecfg := clientv3.Config{
+		Endpoints:   cfg.Endpoints,
+		DialTimeout: 5 * time.Second,
+	}
+	if cfg.TLSEnabled {
+		ecfg.TLS = &tls.Config{InsecureSkipVerify: cfg.TLSInsecureSkipVerify}
+	}
+
+	client, err := clientv3.New(ecfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &etcdBackend{client: client, key: cfg.Key}, nil
+}
+
+func (b *etcdBackend) Leader() (string, error) {
+	resp, err := b.client.Get(context.Background(), b.key)
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Kvs) == 0 {
+		return "", nil
+	}
+	if len(resp.Kvs[0].Value) == 0 {
+		return "", ErrNoLeader
+	}
+	return string(resp.Kvs[0].Value), nil
+}
+
+// Register performs a transactional put that only succeeds when the key's
+// create revision is 0, i.e. no other node has claimed it yet.
+func (b *etcdBackend) Register(url string) (bool, error) {
+	txn := b.client.Txn(context.Background()).
+		If(clientv3.Compare(clientv3.CreateRevision(b.key), "=", 0)).
+		Then(clientv3.OpPut(b.key, url))
+
+	resp, err := txn.Commit()
+	if err != nil {
+		return false, err
+	}
+	return resp.Succeeded, nil
+}
+
+func (b *etcdBackend) Update(url string) error {
+	_, err := b.client.Put(context.Background(), b.key, url)
+	return err
+}
+
+func (b *etcdBackend) Close() error {
+	return b.client.Close()
+}
@@ -0,0 +1,95 @@
+package messaging
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// NodeMeta is a small set of key/value attributes a node publishes about
+// itself so that peers who only know its Raft URL can still reach its
+// HTTP API — seeded with its address, whether it's served over TLS, and
+// its build version.
+type NodeMeta map[string]string
+
+// Well-known NodeMeta keys.
+const (
+	NodeMetaAPIAddr = "api_addr"
+	NodeMetaAPITLS  = "api_tls"
+	NodeMetaVersion = "version"
+)
+
+// clusterCommandMarker prefixes every command ClusterFSM itself handles
+// (currently just SetNodeMeta), so Apply can route on a single leading
+// byte instead of speculatively JSON-decoding every replicated command —
+// including large binary broker segment writes — just to find out
+// whether it's one of ours.
+const clusterCommandMarker = 0xfe
+
+const setNodeMetaCommandType = "setNodeMeta"
+
+type setNodeMetaCommand struct {
+	Type   string   `json:"type"`
+	NodeID uint64   `json:"nodeID"`
+	Meta   NodeMeta `json:"meta"`
+}
+
+// EncodeSetNodeMeta builds the raft log command that publishes meta for
+// nodeID, prefixed with clusterCommandMarker so ClusterFSM.Apply
+// recognizes it without decoding.
+func EncodeSetNodeMeta(nodeID uint64, meta NodeMeta) ([]byte, error) {
+	b, err := json.Marshal(&setNodeMetaCommand{Type: setNodeMetaCommandType, NodeID: nodeID, Meta: meta})
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{clusterCommandMarker}, b...), nil
+}
+
+// ClusterFSM wraps a node's RaftFSM so that, in addition to applying the
+// broker's own topic/segment commands, it tracks per-node metadata
+// published via SetNodeMeta commands. Any command it doesn't recognize is
+// passed through to the wrapped RaftFSM unchanged.
+type ClusterFSM struct {
+	*RaftFSM
+
+	mu   sync.RWMutex
+	meta map[uint64]NodeMeta
+}
+
+// NewClusterFSM returns a ClusterFSM that wraps fsm.
+func NewClusterFSM(fsm *RaftFSM) *ClusterFSM {
+	return &ClusterFSM{RaftFSM: fsm, meta: make(map[uint64]NodeMeta)}
+}
+
+// Apply checks command's leading byte and, if it's a SetNodeMeta command,
+// decodes and applies the remainder directly; otherwise it delegates the
+// whole command unchanged to the wrapped RaftFSM so the broker's normal
+// replication — including large binary topic segment writes — never
+// pays for a JSON decode attempt it was always going to fail.
+func (fsm *ClusterFSM) Apply(index uint64, command []byte) error {
+	if len(command) > 0 && command[0] == clusterCommandMarker {
+		var cmd setNodeMetaCommand
+		if err := json.Unmarshal(command[1:], &cmd); err != nil {
+			return err
+		}
+		fsm.mu.Lock()
+		m := fsm.meta[cmd.NodeID]
+		if m == nil {
+			m = make(NodeMeta)
+		}
+		for k, v := range cmd.Meta {
+			m[k] = v
+		}
+		fsm.meta[cmd.NodeID] = m
+		fsm.mu.Unlock()
+		return nil
+	}
+	return fsm.RaftFSM.Apply(index, command)
+}
+
+// NodeMeta returns the last published metadata for nodeID, or nil if none
+// has been published yet.
+func (fsm *ClusterFSM) NodeMeta(nodeID uint64) NodeMeta {
+	fsm.mu.RLock()
+	defer fsm.mu.RUnlock()
+	return fsm.meta[nodeID]
+}
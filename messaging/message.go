@@ -0,0 +1,55 @@
+package messaging
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// MessageType identifies the kind of payload carried by a Message.
+type MessageType uint8
+
+// Message is a single write buffered by the broker for replication to a
+// subscribed data node. Index is its position in the broker's topic
+// segment stream, used as the resume point for both normal subscriber
+// catch-up and peer recovery replay.
+type Message struct {
+	Index   uint64
+	Type    MessageType
+	TopicID uint64
+	Data    []byte
+}
+
+// MessageDecoder reads the length-prefixed Message records a broker
+// writes to its per-topic segment files.
+type MessageDecoder struct {
+	r io.Reader
+}
+
+// NewMessageDecoder returns a MessageDecoder that reads from r.
+func NewMessageDecoder(r io.Reader) *MessageDecoder {
+	return &MessageDecoder{r: r}
+}
+
+// Decode reads the next Message from the stream into msg.
+func (dec *MessageDecoder) Decode(msg *Message) error {
+	var hdr [17]byte
+	if _, err := io.ReadFull(dec.r, hdr[:]); err != nil {
+		return err
+	}
+
+	msg.Index = binary.BigEndian.Uint64(hdr[0:8])
+	msg.Type = MessageType(hdr[8])
+	msg.TopicID = binary.BigEndian.Uint64(hdr[9:17])
+
+	var dataLen [8]byte
+	if _, err := io.ReadFull(dec.r, dataLen[:]); err != nil {
+		return err
+	}
+
+	data := make([]byte, binary.BigEndian.Uint64(dataLen[:]))
+	if _, err := io.ReadFull(dec.r, data); err != nil {
+		return err
+	}
+	msg.Data = data
+	return nil
+}
@@ -0,0 +1,49 @@
+package influxdb
+
+import (
+	"io"
+
+	"github.com/influxdb/influxdb/messaging"
+)
+
+// RecoverPeer replays buffered writes to a previously-unreachable peer
+// once it reconnects, by walking this broker's segment files for the
+// peer's topic starting just after sinceSeq. It's built on the same
+// TopicReader primitive normal subscribers use to catch up, so replay
+// produces the same messages a connected peer would have received in
+// real time.
+//
+// RecoverPeer returns once it reaches the end of the topic's current
+// segment data; it does not block waiting for new writes. Callers that
+// want to keep replaying as new writes arrive should call it again from
+// where the last call left off.
+func (b *Broker) RecoverPeer(nodeID uint64, sinceSeq uint64, yield func(msg *messaging.Message) error) error {
+	r, err := b.TopicReader(nodeID, sinceSeq)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	dec := messaging.NewMessageDecoder(r)
+	for {
+		var msg messaging.Message
+		if err := dec.Decode(&msg); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err := yield(&msg); err != nil {
+			return err
+		}
+	}
+}
+
+// TopicHeadIndex returns the index of the most recently written message
+// in nodeID's topic, i.e. its current head. It's the broker's own
+// sequence space (distinct from the raft log's index, which also
+// advances for cluster membership changes that never touch a topic), and
+// is what callers should pass as RecoverPeer's notion of "current" when
+// bounding how far a replay is allowed to fall behind.
+func (b *Broker) TopicHeadIndex(nodeID uint64) (uint64, error) {
+	return b.Topic(nodeID).Index(), nil
+}
@@ -0,0 +1,55 @@
+// Package recovery resumably replays a broker's buffered writes to a peer
+// that was unreachable and has just reconnected, checkpointing progress
+// to disk so a restart resumes where it left off instead of replaying
+// from the start (or losing track) every time.
+package recovery
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// checkpoint is the last sequence successfully yielded to a peer.
+type checkpoint struct {
+	NodeID uint64 `json:"nodeID"`
+	Seq    uint64 `json:"seq"`
+}
+
+func checkpointPath(dir string, nodeID uint64) string {
+	return filepath.Join(dir, strconv.FormatUint(nodeID, 10))
+}
+
+// Load returns the last checkpointed sequence for nodeID under dir, or 0
+// if none has been recorded yet.
+func Load(dir string, nodeID uint64) (uint64, error) {
+	b, err := ioutil.ReadFile(checkpointPath(dir, nodeID))
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var cp checkpoint
+	if err := json.Unmarshal(b, &cp); err != nil {
+		return 0, err
+	}
+	return cp.Seq, nil
+}
+
+// Save checkpoints seq as the last sequence successfully yielded to
+// nodeID under dir.
+func Save(dir string, nodeID, seq uint64) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(&checkpoint{NodeID: nodeID, Seq: seq})
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(checkpointPath(dir, nodeID), b, 0644)
+}
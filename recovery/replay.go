@@ -0,0 +1,148 @@
+package recovery
+
+import (
+	"errors"
+	"log"
+	"sync"
+
+	"github.com/influxdb/influxdb"
+	"github.com/influxdb/influxdb/messaging"
+)
+
+var errStopped = errors.New("recovery: replay stopped")
+
+// Progress describes replay progress for a single peer, for the admin
+// endpoint to report.
+type Progress struct {
+	NodeID   uint64 `json:"nodeID"`
+	StartSeq uint64 `json:"startSeq"`
+	LastSeq  uint64 `json:"lastSeq"`
+	Running  bool   `json:"running"`
+}
+
+// Replayer drives Broker.RecoverPeer for each reconnected peer,
+// checkpointing progress under Dir and bounding how far behind a
+// permanently-dead peer is allowed to sit before its backlog is capped.
+type Replayer struct {
+	Broker     *influxdb.Broker
+	Dir        string // normally BrokerDir()/recovery
+	MaxBacklog uint64 // bound on (currentSeq - start); 0 means unlimited
+
+	mu       sync.Mutex
+	stops    map[uint64]chan struct{}
+	progress map[uint64]*Progress
+}
+
+// NewReplayer returns a Replayer that checkpoints under dir.
+func NewReplayer(b *influxdb.Broker, dir string, maxBacklog uint64) *Replayer {
+	return &Replayer{
+		Broker:     b,
+		Dir:        dir,
+		MaxBacklog: maxBacklog,
+		stops:      make(map[uint64]chan struct{}),
+		progress:   make(map[uint64]*Progress),
+	}
+}
+
+// Start begins replaying buffered writes to nodeID in the background,
+// resuming from whichever of its last checkpoint or sinceSeq is further
+// along, and sending each message via send. currentSeq is the topic's
+// current head, used to cap a backlog that exceeds MaxBacklog. A second
+// Start call for a node already being replayed to is a no-op.
+func (r *Replayer) Start(nodeID, sinceSeq, currentSeq uint64, send func(msg *messaging.Message) error) {
+	r.mu.Lock()
+	if _, ok := r.stops[nodeID]; ok {
+		r.mu.Unlock()
+		return
+	}
+	stop := make(chan struct{})
+	r.stops[nodeID] = stop
+	r.mu.Unlock()
+
+	// sinceSeq is the peer's own last-acked sequence, reported fresh on
+	// every reconnect; our checkpoint can be stale (e.g. this process
+	// restarted and missed later acks, or never saw this peer before).
+	// Neither alone is safe to trust: resume from whichever is further
+	// along so we never replay what the peer already has, and never skip
+	// past what our own checkpoint has confirmed sent.
+	start, err := Load(r.Dir, nodeID)
+	if err != nil {
+		start = 0
+	}
+	if sinceSeq > start {
+		start = sinceSeq
+	}
+	if r.MaxBacklog > 0 && currentSeq > start && currentSeq-start > r.MaxBacklog {
+		log.Printf("recovery: node %d backlog exceeds %d, dropping to bound", nodeID, r.MaxBacklog)
+		start = currentSeq - r.MaxBacklog
+	}
+
+	r.mu.Lock()
+	r.progress[nodeID] = &Progress{NodeID: nodeID, StartSeq: start, LastSeq: start, Running: true}
+	r.mu.Unlock()
+
+	go r.run(nodeID, start, stop, send)
+}
+
+func (r *Replayer) run(nodeID, start uint64, stop chan struct{}, send func(msg *messaging.Message) error) {
+	defer func() {
+		r.mu.Lock()
+		delete(r.stops, nodeID)
+		if p, ok := r.progress[nodeID]; ok {
+			p.Running = false
+		}
+		r.mu.Unlock()
+	}()
+
+	err := r.Broker.RecoverPeer(nodeID, start, func(msg *messaging.Message) error {
+		select {
+		case <-stop:
+			return errStopped
+		default:
+		}
+
+		if err := send(msg); err != nil {
+			return err
+		}
+		if err := Save(r.Dir, nodeID, msg.Index); err != nil {
+			return err
+		}
+
+		r.mu.Lock()
+		if p, ok := r.progress[nodeID]; ok {
+			p.LastSeq = msg.Index
+		}
+		r.mu.Unlock()
+		return nil
+	})
+
+	if err != nil && err != errStopped {
+		log.Printf("recovery: replay to node %d stopped: %s", nodeID, err)
+	}
+}
+
+// Stop cancels any in-progress replay to nodeID, e.g. because it
+// disconnected again mid-stream. The last successfully sent sequence
+// stays checkpointed, so a later reconnect resumes from there.
+func (r *Replayer) Stop(nodeID uint64) {
+	r.mu.Lock()
+	stop, ok := r.stops[nodeID]
+	r.mu.Unlock()
+	if ok {
+		close(stop)
+	}
+}
+
+// Progress returns a snapshot of replay progress for every peer that has
+// been replayed to in this process, for the admin endpoint.
+func (r *Replayer) Progress() []*Progress {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make([]*Progress, 0, len(r.progress))
+	for _, p := range r.progress {
+		cp := *p
+		out = append(out, &cp)
+	}
+	return out
+}
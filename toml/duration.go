@@ -0,0 +1,27 @@
+package toml
+
+import "time"
+
+// Duration is a time.Duration that can be unmarshaled from a TOML string
+// such as "10s" or "1h30m", rather than only an integer number of nanoseconds.
+type Duration time.Duration
+
+// UnmarshalText parses a TOML string value into a Duration.
+func (d *Duration) UnmarshalText(text []byte) error {
+	duration, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+// MarshalText formats the Duration using its string representation.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// String returns the string representation of the duration.
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}